@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"fmt"
+	"time"
+)
+
+// IssueTokenUsage is the docopt usage block for the `issue-token`
+// subcommand; splice it into pyrevit-telemetry-cli's top-level usage
+// string alongside the other subcommands.
+const IssueTokenUsage = `
+Usage:
+  pyrevit-telemetry-cli issue-token --private-key=<path> --subject=<clone> [--ttl=<duration>]
+
+Options:
+  --private-key=<path>   Path to the RS256 private key used to sign the token.
+  --subject=<clone>      Clone or username the token is valid for.
+  --ttl=<duration>       Token lifetime, as a Go duration string [default: 8760h].
+`
+
+// RunIssueTokenCommand signs a token for the `issue-token` subcommand's
+// parsed docopt arguments and returns it; printing it, so an admin can
+// hand it to a pyRevit clone, is left to the caller.
+func RunIssueTokenCommand(privateKeyPath string, subject string, ttlArg string) (string, error) {
+	ttl, err := time.ParseDuration(ttlArg)
+	if err != nil {
+		return "", fmt.Errorf("invalid --ttl %q: %w", ttlArg, err)
+	}
+
+	token, err := IssueToken(privateKeyPath, subject, ttl)
+	if err != nil {
+		return "", fmt.Errorf("failed issuing token: %w", err)
+	}
+	return token, nil
+}
@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+)
+
+// recordIdentity pulls just the clone/username claim out of an incoming
+// script or event record, without needing to know its full schema.
+type recordIdentity struct {
+	Clone    string `json:"clone"`
+	UserName string `json:"username"`
+}
+
+// RequireMatchingSubject wraps an ingest handler with Middleware and, once
+// the bearer token is valid, also rejects the request if the record's own
+// Clone/UserName doesn't match the token's subject. This is what actually
+// closes the "anyone who can reach the URL can pollute the DB" hole;
+// Middleware alone only proves the token is valid, not that it belongs to
+// the clone posting the record.
+func RequireMatchingSubject(cfg Config, verifier *Verifier, next http.HandlerFunc) http.HandlerFunc {
+	return Middleware(cfg, verifier, func(writer http.ResponseWriter, request *http.Request) {
+		if !cfg.Enabled {
+			next(writer, request)
+			return
+		}
+
+		body, err := ioutil.ReadAll(request.Body)
+		if err != nil {
+			http.Error(writer, "failed reading request body", http.StatusBadRequest)
+			return
+		}
+		request.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		var identity recordIdentity
+		if err := json.Unmarshal(body, &identity); err != nil {
+			http.Error(writer, "failed reading record clone/username", http.StatusBadRequest)
+			return
+		}
+
+		if !MatchesSubject(request.Context(), identity.Clone, identity.UserName) {
+			http.Error(writer, "token subject does not match record clone/username", http.StatusForbidden)
+			return
+		}
+
+		next(writer, request)
+	})
+}
@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeTestKeys(t *testing.T) (privatePath, publicPath string) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed generating rsa key: %v", err)
+	}
+
+	privateFile, err := ioutil.TempFile("", "jwt-private-*.pem")
+	if err != nil {
+		t.Fatalf("failed creating temp private key file: %v", err)
+	}
+	defer privateFile.Close()
+	if err := pem.Encode(privateFile, &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}); err != nil {
+		t.Fatalf("failed encoding private key: %v", err)
+	}
+
+	publicBytes, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("failed marshalling public key: %v", err)
+	}
+	publicFile, err := ioutil.TempFile("", "jwt-public-*.pem")
+	if err != nil {
+		t.Fatalf("failed creating temp public key file: %v", err)
+	}
+	defer publicFile.Close()
+	if err := pem.Encode(publicFile, &pem.Block{Type: "PUBLIC KEY", Bytes: publicBytes}); err != nil {
+		t.Fatalf("failed encoding public key: %v", err)
+	}
+
+	t.Cleanup(func() {
+		os.Remove(privateFile.Name())
+		os.Remove(publicFile.Name())
+	})
+	return privateFile.Name(), publicFile.Name()
+}
+
+func TestRequireMatchingSubjectRejectsMismatchedClone(t *testing.T) {
+	privatePath, publicPath := writeTestKeys(t)
+
+	verifier, err := NewVerifier(publicPath)
+	if err != nil {
+		t.Fatalf("failed building verifier: %v", err)
+	}
+	cfg := Config{Enabled: true, PublicKeyPath: publicPath}
+
+	token, err := IssueToken(privatePath, "clone-a", time.Hour)
+	if err != nil {
+		t.Fatalf("failed issuing token: %v", err)
+	}
+
+	called := false
+	handler := RequireMatchingSubject(cfg, verifier, func(http.ResponseWriter, *http.Request) {
+		called = true
+	})
+
+	request := httptest.NewRequest(http.MethodPost, "/ingest", strings.NewReader(`{"clone":"clone-b","username":"someone"}`))
+	request.Header.Set("Authorization", "Bearer "+token)
+	recorder := httptest.NewRecorder()
+
+	handler(recorder, request)
+
+	if called {
+		t.Fatalf("expected handler not to be called for mismatched clone")
+	}
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", recorder.Code)
+	}
+}
+
+func TestRequireMatchingSubjectAllowsMatchingClone(t *testing.T) {
+	privatePath, publicPath := writeTestKeys(t)
+
+	verifier, err := NewVerifier(publicPath)
+	if err != nil {
+		t.Fatalf("failed building verifier: %v", err)
+	}
+	cfg := Config{Enabled: true, PublicKeyPath: publicPath}
+
+	token, err := IssueToken(privatePath, "clone-a", time.Hour)
+	if err != nil {
+		t.Fatalf("failed issuing token: %v", err)
+	}
+
+	called := false
+	handler := RequireMatchingSubject(cfg, verifier, func(http.ResponseWriter, *http.Request) {
+		called = true
+	})
+
+	request := httptest.NewRequest(http.MethodPost, "/ingest", strings.NewReader(`{"clone":"clone-a","username":"someone"}`))
+	request.Header.Set("Authorization", "Bearer "+token)
+	recorder := httptest.NewRecorder()
+
+	handler(recorder, request)
+
+	if !called {
+		t.Fatalf("expected handler to be called for matching clone")
+	}
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", recorder.Code)
+	}
+}
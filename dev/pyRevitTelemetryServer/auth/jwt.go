@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Config gates JWT authentication on the ingest endpoints. When Enabled
+// is false the server behaves exactly as before: anyone reaching the URL
+// can post records.
+type Config struct {
+	Enabled       bool   `json:"jwt_enabled"`
+	PublicKeyPath string `json:"jwt_public_key"`
+}
+
+// Claims is the JWT payload issued to a pyRevit clone. Subject carries
+// the clone (or username) the token is valid for.
+type Claims struct {
+	jwt.RegisteredClaims
+}
+
+type subjectKey struct{}
+
+// Verifier validates bearer tokens against a single RS256 public key.
+type Verifier struct {
+	publicKey interface{}
+}
+
+// NewVerifier loads the RS256 public key at publicKeyPath.
+func NewVerifier(publicKeyPath string) (*Verifier, error) {
+	keyBytes, err := ioutil.ReadFile(publicKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading jwt public key %s: %w", publicKeyPath, err)
+	}
+	key, err := jwt.ParseRSAPublicKeyFromPEM(keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing jwt public key %s: %w", publicKeyPath, err)
+	}
+	return &Verifier{publicKey: key}, nil
+}
+
+// Middleware rejects POST requests without a valid `Authorization:
+// Bearer` token when cfg.Enabled is set, and otherwise stores the
+// token's subject on the request context for the handler to check
+// against the record's Clone/UserName.
+func Middleware(cfg Config, verifier *Verifier, next http.HandlerFunc) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		if !cfg.Enabled {
+			next(writer, request)
+			return
+		}
+
+		header := request.Header.Get("Authorization")
+		if !strings.HasPrefix(header, "Bearer ") {
+			http.Error(writer, "missing bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		claims := &Claims{}
+		token, err := jwt.ParseWithClaims(strings.TrimPrefix(header, "Bearer "), claims, func(token *jwt.Token) (interface{}, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+				return nil, fmt.Errorf("unexpected signing method %q, want RS256", token.Header["alg"])
+			}
+			return verifier.publicKey, nil
+		})
+		if err != nil || !token.Valid {
+			http.Error(writer, "invalid bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		ctx := context.WithValue(request.Context(), subjectKey{}, claims.Subject)
+		next(writer, request.WithContext(ctx))
+	}
+}
+
+// SubjectFromContext returns the clone/username that the bearer token on
+// request was issued for, set by Middleware.
+func SubjectFromContext(ctx context.Context) (string, bool) {
+	subject, ok := ctx.Value(subjectKey{}).(string)
+	return subject, ok
+}
+
+// MatchesSubject reports whether the authenticated token subject matches
+// the record's own Clone or UserName; ingest handlers should reject the
+// record when this is false so one clone can't pollute another's data.
+func MatchesSubject(ctx context.Context, clone string, userName string) bool {
+	subject, ok := SubjectFromContext(ctx)
+	if !ok {
+		return false
+	}
+	return subject == clone || subject == userName
+}
+
+// IssueToken signs a token for subject (typically a clone id) with the
+// RS256 private key at privateKeyPath, valid for ttl. This backs the
+// `pyrevit-telemetry-cli issue-token` subcommand that admins use to hand
+// tokens out to pyRevit clones.
+func IssueToken(privateKeyPath string, subject string, ttl time.Duration) (string, error) {
+	keyBytes, err := ioutil.ReadFile(privateKeyPath)
+	if err != nil {
+		return "", fmt.Errorf("failed reading jwt private key %s: %w", privateKeyPath, err)
+	}
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(keyBytes)
+	if err != nil {
+		return "", fmt.Errorf("failed parsing jwt private key %s: %w", privateKeyPath, err)
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   subject,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	})
+	return token.SignedString(privateKey)
+}
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	scriptExecutionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "pyrevit_script_executions_total",
+		Help: "Total number of pyRevit script executions ingested by the telemetry server.",
+	}, []string{"extension", "command", "revit", "engine", "result"})
+
+	scriptErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "pyrevit_script_errors_total",
+		Help: "Total number of pyRevit script executions that returned a non-zero result code.",
+	}, []string{"extension", "command", "revit", "engine", "result"})
+
+	eventTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "pyrevit_event_total",
+		Help: "Total number of pyRevit application events ingested by the telemetry server.",
+	}, []string{"type", "doctype"})
+
+	commandDurationMs = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "pyrevit_command_duration_milliseconds",
+		Help:    "Duration of pyRevit command executions, in milliseconds.",
+		Buckets: prometheus.ExponentialBuckets(10, 2, 12),
+	}, []string{"extension", "command"})
+)
+
+// ObserveScript updates the Prometheus counters and duration histogram
+// for an ingested script record. Takes plain fields rather than a
+// persistence.ScriptTelemetryRecordV2 so this package stays independent
+// of the persistence layer, which calls it from Dispatcher as records
+// flow through.
+func ObserveScript(extension string, command string, revit string, engine string, resultCode int, durationMs int64) {
+	result := "ok"
+	if resultCode != 0 {
+		result = "error"
+	}
+
+	labels := prometheus.Labels{
+		"extension": extension,
+		"command":   command,
+		"revit":     revit,
+		"engine":    engine,
+		"result":    result,
+	}
+	scriptExecutionsTotal.With(labels).Inc()
+	if resultCode != 0 {
+		scriptErrorsTotal.With(labels).Inc()
+	}
+	if durationMs > 0 {
+		commandDurationMs.WithLabelValues(extension, command).Observe(float64(durationMs))
+	}
+}
+
+// ObserveEvent updates the pyrevit_event_total counter for an ingested
+// event record.
+func ObserveEvent(eventType string, docType string) {
+	eventTotal.With(prometheus.Labels{
+		"type":    eventType,
+		"doctype": docType,
+	}).Inc()
+}
+
+// Handler serves the registered collectors on a Prometheus-compatible
+// /metrics endpoint; mount it on the server's gorilla/mux router.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,10 @@
+package metrics
+
+import "github.com/gorilla/mux"
+
+// RegisterRoutes mounts Handler on router at the conventional /metrics
+// path, so a Prometheus scraper can be pointed at this server like any
+// other instrumented service.
+func RegisterRoutes(router *mux.Router) {
+	router.Handle("/metrics", Handler())
+}
@@ -0,0 +1,40 @@
+package persistence
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// DryRunMigrateUsage is the docopt usage block for the
+// `--dry-run-migrate` subcommand; splice it into pyrevit-telemetry-cli's
+// top-level usage string alongside the other subcommands.
+const DryRunMigrateUsage = `
+Usage:
+  pyrevit-telemetry-cli --dry-run-migrate --collection=<path>
+
+Options:
+  --collection=<path>   Path to a newline-delimited JSON export of stored script records.
+`
+
+// RunDryRunMigrateCommand reads one JSON script record per line from
+// collection and reports the per-record validation/migration errors for
+// the `--dry-run-migrate` subcommand's parsed docopt arguments, without
+// writing anything back.
+func RunDryRunMigrateCommand(collection io.Reader) ([]DryRunMigrateResult, error) {
+	var records [][]byte
+	scanner := bufio.NewScanner(collection)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		records = append(records, append([]byte(nil), line...))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed reading collection: %w", err)
+	}
+
+	return DryRunMigrateScripts(records), nil
+}
@@ -0,0 +1,95 @@
+package persistence
+
+import (
+	"log"
+	"sync/atomic"
+	"time"
+
+	"pyrevittelemetryserver/metrics"
+)
+
+const writerQueueSize = 256
+const maxWriteRetries = 3
+
+// Dispatcher fans out every ingested record to N configured writers in
+// parallel. Each writer gets its own buffered channel and retry loop, so
+// a slow or unreachable sink cannot block ingest for the others. Sends
+// to a full queue are dropped rather than blocked, so a dead sink can't
+// back-pressure ingest or the other writers either.
+type Dispatcher struct {
+	queues  []chan interface{}
+	dropped []uint64
+}
+
+// NewDispatcher starts one drain goroutine per writer and returns a
+// Dispatcher that feeds all of them.
+func NewDispatcher(writers []Writer) *Dispatcher {
+	dispatcher := &Dispatcher{
+		dropped: make([]uint64, len(writers)),
+	}
+	for _, writer := range writers {
+		queue := make(chan interface{}, writerQueueSize)
+		dispatcher.queues = append(dispatcher.queues, queue)
+		go dispatcher.drain(writer, queue)
+	}
+	return dispatcher
+}
+
+// DroppedCount returns how many records have been dropped for the
+// writer at index, because its queue was full.
+func (dispatcher *Dispatcher) DroppedCount(index int) uint64 {
+	return atomic.LoadUint64(&dispatcher.dropped[index])
+}
+
+func (dispatcher *Dispatcher) drain(writer Writer, queue chan interface{}) {
+	for record := range queue {
+		dispatcher.writeWithRetry(writer, record)
+	}
+}
+
+func (dispatcher *Dispatcher) writeWithRetry(writer Writer, record interface{}) {
+	var err error
+	for attempt := 0; attempt < maxWriteRetries; attempt++ {
+		switch logrec := record.(type) {
+		case ScriptTelemetryRecordV2:
+			err = writer.WriteScript(logrec)
+		case EventTelemetryRecordV2:
+			err = writer.WriteEvent(logrec)
+		}
+		if err == nil {
+			return
+		}
+		time.Sleep(time.Duration(attempt+1) * time.Second)
+	}
+	log.Printf("giving up writing record after %d attempts: %v", maxWriteRetries, err)
+}
+
+// DispatchScript queues a script record for every configured writer,
+// dropping it for any writer whose queue is currently full.
+func (dispatcher *Dispatcher) DispatchScript(logrec ScriptTelemetryRecordV2) {
+	dispatcher.dispatch(logrec)
+}
+
+// DispatchEvent queues an event record for every configured writer,
+// dropping it for any writer whose queue is currently full.
+func (dispatcher *Dispatcher) DispatchEvent(logrec EventTelemetryRecordV2) {
+	dispatcher.dispatch(logrec)
+}
+
+func (dispatcher *Dispatcher) dispatch(record interface{}) {
+	switch logrec := record.(type) {
+	case ScriptTelemetryRecordV2:
+		metrics.ObserveScript(logrec.ExtensionName, logrec.CommandUniqueName, logrec.RevitVersion, logrec.TraceInfo.EngineInfo.Type, logrec.ResultCode, logrec.DurationMs)
+	case EventTelemetryRecordV2:
+		metrics.ObserveEvent(logrec.EventType, logrec.DocumentType)
+	}
+
+	for index, queue := range dispatcher.queues {
+		select {
+		case queue <- record:
+		default:
+			dropped := atomic.AddUint64(&dispatcher.dropped[index], 1)
+			log.Printf("writer %d queue full, dropping record (dropped so far: %d)", index, dropped)
+		}
+	}
+}
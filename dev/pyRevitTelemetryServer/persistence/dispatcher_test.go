@@ -0,0 +1,57 @@
+package persistence
+
+import (
+	"testing"
+	"time"
+)
+
+// blockingWriter's WriteScript never returns, so its queue fills up and
+// every subsequent dispatch must be dropped rather than blocked.
+type blockingWriter struct {
+	started chan struct{}
+}
+
+func (writer *blockingWriter) Init(connectionString string) error { return nil }
+
+func (writer *blockingWriter) WriteScript(logrec ScriptTelemetryRecordV2) error {
+	close(writer.started)
+	select {}
+}
+
+func (writer *blockingWriter) WriteEvent(logrec EventTelemetryRecordV2) error {
+	select {}
+}
+
+func (writer *blockingWriter) Close() error { return nil }
+
+func TestDispatchScriptDoesNotBlockOnAStuckWriter(t *testing.T) {
+	writer := &blockingWriter{started: make(chan struct{})}
+	dispatcher := NewDispatcher([]Writer{writer})
+
+	logrec := ScriptTelemetryRecordV2{CommandUniqueName: "ext.cmd"}
+	for i := 0; i < writerQueueSize*2; i++ {
+		dispatcher.DispatchScript(logrec)
+	}
+
+	select {
+	case <-writer.started:
+	case <-time.After(time.Second):
+		t.Fatal("writer never started processing a record")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		dispatcher.DispatchScript(logrec)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("DispatchScript blocked on a stuck writer instead of dropping the record")
+	}
+
+	if dispatcher.DroppedCount(0) == 0 {
+		t.Fatal("expected at least one record to be dropped for the stuck writer's full queue")
+	}
+}
@@ -97,6 +97,16 @@ type ScriptTelemetryRecordV2 struct {
 	CommandResults    map[string]interface{} `json:"commandresults" bson:"commandresults" valid:"-"`
 	ScriptPath        string                 `json:"scriptpath" bson:"scriptpath" valid:"-"`
 	TraceInfo         TraceInfoV2            `json:"trace" bson:"trace" valid:"-"` // revised in v2.0
+	DurationMs        int64                  `json:"duration_ms,omitempty" bson:"duration_ms" valid:"-"` // added in v2.1
+}
+
+// EnsureDurationMs resets a negative DurationMs to 0. Records from
+// clients older than v2.1 never send duration_ms, so it is already 0 for
+// them. The 2.0 -> 2.1 schema migrator in schema.go calls it.
+func (logrec *ScriptTelemetryRecordV2) EnsureDurationMs() {
+	if logrec.DurationMs < 0 {
+		logrec.DurationMs = 0
+	}
 }
 
 func (logrec ScriptTelemetryRecordV2) PrintRecordInfo(logger *cli.Logger, message string) {
@@ -159,8 +169,15 @@ func (logrec EventTelemetryRecordV2) PrintRecordInfo(logger *cli.Logger, message
 	}
 }
 
-func (logrec EventTelemetryRecordV2) Validate() {
-	// todo: validate by schema version
-	if logrec.RecordMeta.SchemaVersion == "2.0" {
+func (logrec EventTelemetryRecordV2) Validate() error {
+	if logrec.RecordMeta.SchemaVersion != "2.0" {
+		return fmt.Errorf("unknown event schema version %q", logrec.RecordMeta.SchemaVersion)
+	}
+	if logrec.EventType == "" {
+		return fmt.Errorf("event record missing required field: type")
+	}
+	if logrec.TimeStamp == "" {
+		return fmt.Errorf("event record missing required field: timestamp")
 	}
+	return nil
 }
@@ -0,0 +1,153 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func init() {
+	RegisterWriter("otlp", func() Writer { return &OTLPExporter{} })
+}
+
+// OTLPConfig holds the endpoint and headers used to push telemetry
+// records to an OTel Collector, read from the server config file.
+type OTLPConfig struct {
+	Endpoint string            `json:"otlp_endpoint"`
+	Headers  map[string]string `json:"otlp_headers"`
+	Insecure bool              `json:"otlp_insecure"`
+}
+
+// OTLPExporter maps ScriptTelemetryRecordV2 and EventTelemetryRecordV2
+// records onto OTel spans and pushes them to the configured collector,
+// so sites can point pyRevit telemetry at Tempo/Jaeger/Datadog without a
+// separate pipeline.
+type OTLPExporter struct {
+	tracer   trace.Tracer
+	provider *sdktrace.TracerProvider
+}
+
+// OTLPConfigFromFile decodes the otlp_endpoint/otlp_headers/otlp_insecure
+// keys out of the server's shared JSON config file into an OTLPConfig,
+// the same way every other server setting is configured. Init's
+// "otlp://" connection-string scheme, below, is how the writer registry
+// constructs an OTLPExporter and doesn't replace this — sites running
+// the exporter standalone, outside the writer registry, configure it
+// straight from the config file via this helper.
+func OTLPConfigFromFile(configFile []byte) (OTLPConfig, error) {
+	var cfg OTLPConfig
+	if err := json.Unmarshal(configFile, &cfg); err != nil {
+		return OTLPConfig{}, fmt.Errorf("failed reading otlp config: %w", err)
+	}
+	return cfg, nil
+}
+
+// NewOTLPExporter dials the collector described by cfg and returns an
+// exporter ready to accept records.
+func NewOTLPExporter(cfg OTLPConfig) (*OTLPExporter, error) {
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint)}
+	if cfg.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(cfg.Headers))
+	}
+
+	exp, err := otlptrace.New(context.Background(), otlptracehttp.NewClient(opts...))
+	if err != nil {
+		return nil, fmt.Errorf("failed connecting to otlp collector at %s: %w", cfg.Endpoint, err)
+	}
+
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exp))
+	return &OTLPExporter{
+		tracer:   provider.Tracer("pyrevit-telemetry-server"),
+		provider: provider,
+	}, nil
+}
+
+// Init implements Writer. connectionString is a URL of the form
+// "otlp://collector:4318?insecure=true&header.x-api-key=secret".
+func (exporter *OTLPExporter) Init(connectionString string) error {
+	parsed, err := url.Parse(connectionString)
+	if err != nil {
+		return fmt.Errorf("failed parsing otlp connection string: %w", err)
+	}
+
+	cfg := OTLPConfig{
+		Endpoint: parsed.Host,
+		Headers:  map[string]string{},
+		Insecure: parsed.Query().Get("insecure") == "true",
+	}
+	for key, values := range parsed.Query() {
+		if name, ok := headerParamName(key); ok && len(values) > 0 {
+			cfg.Headers[name] = values[0]
+		}
+	}
+
+	built, err := NewOTLPExporter(cfg)
+	if err != nil {
+		return err
+	}
+	exporter.tracer = built.tracer
+	exporter.provider = built.provider
+	return nil
+}
+
+func headerParamName(key string) (string, bool) {
+	const prefix = "header."
+	if len(key) > len(prefix) && key[:len(prefix)] == prefix {
+		return key[len(prefix):], true
+	}
+	return "", false
+}
+
+// WriteScript implements Writer, emitting a ScriptTelemetryRecordV2 as a
+// single span named after CommandUniqueName, with a non-zero ResultCode
+// reported as a span error carrying TraceInfo.Message.
+func (exporter *OTLPExporter) WriteScript(logrec ScriptTelemetryRecordV2) error {
+	_, span := exporter.tracer.Start(context.Background(), logrec.CommandUniqueName)
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("pyrevit.timestamp", logrec.TimeStamp),
+		attribute.String("pyrevit.session_id", logrec.SessionId),
+		attribute.String("pyrevit.engine.type", logrec.TraceInfo.EngineInfo.Type),
+		attribute.String("pyrevit.engine.version", logrec.TraceInfo.EngineInfo.Version),
+	)
+	for key, value := range logrec.CommandResults {
+		span.SetAttributes(attribute.String(fmt.Sprintf("pyrevit.commandresults.%s", key), fmt.Sprintf("%v", value)))
+	}
+
+	if logrec.ResultCode != 0 {
+		span.SetStatus(codes.Error, logrec.TraceInfo.Message)
+	}
+	return nil
+}
+
+// WriteEvent implements Writer, emitting an EventTelemetryRecordV2 as a
+// span named after the event type.
+func (exporter *OTLPExporter) WriteEvent(logrec EventTelemetryRecordV2) error {
+	_, span := exporter.tracer.Start(context.Background(), logrec.EventType)
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("pyrevit.timestamp", logrec.TimeStamp),
+		attribute.String("pyrevit.doctype", logrec.DocumentType),
+		attribute.String("pyrevit.docname", logrec.DocumentName),
+	)
+	return nil
+}
+
+// Close implements Writer, flushing any pending spans and releasing
+// exporter resources.
+func (exporter *OTLPExporter) Close() error {
+	return exporter.provider.Shutdown(context.Background())
+}
@@ -0,0 +1,235 @@
+package persistence
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// CurrentScriptSchemaVersion is the schema version ScriptTelemetryRecordV2
+// records are migrated to before being handed to writers.
+const CurrentScriptSchemaVersion = "2.1"
+
+// SchemaDescriptor binds one schema version to its Go type, its
+// validator, and a migrator that upgrades a record of this version to
+// the next one. Adding schema v3 means registering one descriptor here,
+// rather than editing the HTTP handlers, DB writers, and printers.
+type SchemaDescriptor struct {
+	Version       string
+	New           func() interface{}
+	Validate      func(record interface{}) error
+	MigrateToNext func(record interface{}) (interface{}, error)
+}
+
+var scriptSchemaRegistry = map[string]SchemaDescriptor{}
+
+// RegisterScriptSchema adds a descriptor for one ScriptTelemetryRecordV2
+// schema version.
+func RegisterScriptSchema(descriptor SchemaDescriptor) {
+	scriptSchemaRegistry[descriptor.Version] = descriptor
+}
+
+func init() {
+	RegisterScriptSchema(SchemaDescriptor{
+		// pre-dates RecordMeta.SchemaVersion; ResolveScript falls back to
+		// this version for raw payloads with no "meta.schema" field.
+		Version: "1.0",
+		New:     func() interface{} { return &ScriptTelemetryRecordV1{} },
+		Validate: func(record interface{}) error {
+			record.(*ScriptTelemetryRecordV1).Validate()
+			return nil
+		},
+		MigrateToNext: func(record interface{}) (interface{}, error) {
+			v1 := record.(*ScriptTelemetryRecordV1)
+			results := make(map[string]interface{}, len(v1.CommandResults))
+			for key, value := range v1.CommandResults {
+				results[key] = value
+			}
+			return &ScriptTelemetryRecordV2{
+				RecordMeta:        RecordMetaV2{SchemaVersion: "2.0"},
+				TimeStamp:         fmt.Sprintf("%sT%sZ", v1.Date, v1.Time),
+				UserName:          v1.UserName,
+				RevitVersion:      v1.RevitVersion,
+				RevitBuild:        v1.RevitBuild,
+				SessionId:         v1.SessionId,
+				PyRevitVersion:    v1.PyRevitVersion,
+				IsDebugMode:       v1.IsDebugMode,
+				IsConfigMode:      v1.IsConfigMode,
+				CommandName:       v1.CommandName,
+				CommandUniqueName: v1.CommandUniqueName,
+				BundleName:        v1.BundleName,
+				ExtensionName:     v1.ExtensionName,
+				ResultCode:        v1.ResultCode,
+				CommandResults:    results,
+				ScriptPath:        v1.ScriptPath,
+				TraceInfo: TraceInfoV2{
+					EngineInfo: EngineInfoV2{
+						Version:  v1.TraceInfo.EngineInfo.Version,
+						SysPaths: v1.TraceInfo.EngineInfo.SysPaths,
+					},
+				},
+			}, nil
+		},
+	})
+	RegisterScriptSchema(SchemaDescriptor{
+		Version: "2.0",
+		New:     func() interface{} { return &ScriptTelemetryRecordV2{} },
+		Validate: func(record interface{}) error {
+			return record.(*ScriptTelemetryRecordV2).Validate()
+		},
+		MigrateToNext: func(record interface{}) (interface{}, error) {
+			logrec := record.(*ScriptTelemetryRecordV2)
+			logrec.EnsureDurationMs()
+			logrec.RecordMeta.SchemaVersion = "2.1"
+			return logrec, nil
+		},
+	})
+	RegisterScriptSchema(SchemaDescriptor{
+		Version: CurrentScriptSchemaVersion,
+		New:     func() interface{} { return &ScriptTelemetryRecordV2{} },
+		Validate: func(record interface{}) error {
+			return record.(*ScriptTelemetryRecordV2).Validate()
+		},
+		MigrateToNext: nil,
+	})
+}
+
+// CurrentEventSchemaVersion is the schema version EventTelemetryRecordV2
+// records are expected to already be at; no event migrators exist yet.
+const CurrentEventSchemaVersion = "2.0"
+
+var eventSchemaRegistry = map[string]SchemaDescriptor{}
+
+// RegisterEventSchema adds a descriptor for one EventTelemetryRecordV2
+// schema version.
+func RegisterEventSchema(descriptor SchemaDescriptor) {
+	eventSchemaRegistry[descriptor.Version] = descriptor
+}
+
+func init() {
+	RegisterEventSchema(SchemaDescriptor{
+		Version: CurrentEventSchemaVersion,
+		New:     func() interface{} { return &EventTelemetryRecordV2{} },
+		Validate: func(record interface{}) error {
+			return record.(*EventTelemetryRecordV2).Validate()
+		},
+		MigrateToNext: nil,
+	})
+}
+
+type metaEnvelope struct {
+	RecordMeta RecordMetaV2 `json:"meta"`
+}
+
+// ResolveScript unmarshals a raw script record, looks up its schema
+// descriptor by meta.schema (falling back to "1.0" for records that
+// pre-date that field), validates it, then chain-applies migrators
+// until it reaches CurrentScriptSchemaVersion. Old clients keep working
+// without the router needing to know about every past schema version.
+func ResolveScript(raw []byte) (*ScriptTelemetryRecordV2, error) {
+	var envelope metaEnvelope
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return nil, fmt.Errorf("failed reading schema version: %w", err)
+	}
+
+	version := envelope.RecordMeta.SchemaVersion
+	if version == "" {
+		version = "1.0"
+	}
+
+	descriptor, ok := scriptSchemaRegistry[version]
+	if !ok {
+		return nil, fmt.Errorf("unknown schema version %q", version)
+	}
+
+	record := descriptor.New()
+	if err := json.Unmarshal(raw, record); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling record: %w", err)
+	}
+	if err := descriptor.Validate(record); err != nil {
+		return nil, fmt.Errorf("validation failed for schema %s: %w", descriptor.Version, err)
+	}
+
+	for descriptor.MigrateToNext != nil {
+		migrated, err := descriptor.MigrateToNext(record)
+		if err != nil {
+			return nil, fmt.Errorf("migration from schema %s failed: %w", descriptor.Version, err)
+		}
+		record = migrated
+
+		nextVersion := record.(*ScriptTelemetryRecordV2).RecordMeta.SchemaVersion
+		next, ok := scriptSchemaRegistry[nextVersion]
+		if !ok {
+			return nil, fmt.Errorf("migrated to unknown schema version %q", nextVersion)
+		}
+		if err := next.Validate(record); err != nil {
+			return nil, fmt.Errorf("validation failed for migrated schema %s: %w", next.Version, err)
+		}
+		descriptor = next
+	}
+
+	return record.(*ScriptTelemetryRecordV2), nil
+}
+
+// ResolveEvent unmarshals a raw event record, looks up its schema
+// descriptor by meta.schema, validates it, then chain-applies migrators
+// until it reaches CurrentEventSchemaVersion.
+func ResolveEvent(raw []byte) (*EventTelemetryRecordV2, error) {
+	var envelope metaEnvelope
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return nil, fmt.Errorf("failed reading schema version: %w", err)
+	}
+
+	descriptor, ok := eventSchemaRegistry[envelope.RecordMeta.SchemaVersion]
+	if !ok {
+		return nil, fmt.Errorf("unknown schema version %q", envelope.RecordMeta.SchemaVersion)
+	}
+
+	record := descriptor.New()
+	if err := json.Unmarshal(raw, record); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling record: %w", err)
+	}
+	if err := descriptor.Validate(record); err != nil {
+		return nil, fmt.Errorf("validation failed for schema %s: %w", descriptor.Version, err)
+	}
+
+	for descriptor.MigrateToNext != nil {
+		migrated, err := descriptor.MigrateToNext(record)
+		if err != nil {
+			return nil, fmt.Errorf("migration from schema %s failed: %w", descriptor.Version, err)
+		}
+		record = migrated
+
+		nextVersion := record.(*EventTelemetryRecordV2).RecordMeta.SchemaVersion
+		next, ok := eventSchemaRegistry[nextVersion]
+		if !ok {
+			return nil, fmt.Errorf("migrated to unknown schema version %q", nextVersion)
+		}
+		if err := next.Validate(record); err != nil {
+			return nil, fmt.Errorf("validation failed for migrated schema %s: %w", next.Version, err)
+		}
+		descriptor = next
+	}
+
+	return record.(*EventTelemetryRecordV2), nil
+}
+
+// DryRunMigrateResult reports the outcome of running one stored record
+// through ResolveScript without writing anything back.
+type DryRunMigrateResult struct {
+	Index int
+	Error error
+}
+
+// DryRunMigrateScripts runs every raw record in records through
+// ResolveScript and reports validation/migration errors per record.
+// RunDryRunMigrateCommand, in cli.go, wraps this for the
+// `--dry-run-migrate` CLI mode.
+func DryRunMigrateScripts(records [][]byte) []DryRunMigrateResult {
+	var results []DryRunMigrateResult
+	for index, raw := range records {
+		if _, err := ResolveScript(raw); err != nil {
+			results = append(results, DryRunMigrateResult{Index: index, Error: err})
+		}
+	}
+	return results
+}
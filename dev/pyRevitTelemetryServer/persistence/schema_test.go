@@ -0,0 +1,80 @@
+package persistence
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestResolveScriptMigratesV1ThroughToCurrent(t *testing.T) {
+	raw := []byte(`{
+		"date": "2026-01-01",
+		"time": "12:00:00",
+		"username": "jdoe",
+		"revit": "2024",
+		"revitbuild": "20230101_0000(x64)",
+		"sessionid": "b5f858b1-2f45-4d3e-9a3a-1f6f6c2e9a10",
+		"pyrevit": "4.8",
+		"commanduniquename": "ext.cmd",
+		"commandextension": "ext",
+		"resultcode": 0,
+		"commandresults": {"k": "v"},
+		"trace": {"engine": {"version": "3.4.1"}}
+	}`)
+
+	record, err := ResolveScript(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record.RecordMeta.SchemaVersion != CurrentScriptSchemaVersion {
+		t.Fatalf("expected record migrated to %s, got %s", CurrentScriptSchemaVersion, record.RecordMeta.SchemaVersion)
+	}
+	if record.UserName != "jdoe" {
+		t.Fatalf("expected username carried over from v1, got %q", record.UserName)
+	}
+	if record.DurationMs != 0 {
+		t.Fatalf("expected duration_ms to default to 0 for a migrated v1 record, got %d", record.DurationMs)
+	}
+}
+
+func TestResolveScriptRejectsInvalidMigratedRecord(t *testing.T) {
+	const brokenVersion = "test-broken"
+	RegisterScriptSchema(SchemaDescriptor{
+		Version: "test-start",
+		New:     func() interface{} { return &ScriptTelemetryRecordV2{} },
+		Validate: func(record interface{}) error {
+			return record.(*ScriptTelemetryRecordV2).Validate()
+		},
+		MigrateToNext: func(record interface{}) (interface{}, error) {
+			logrec := record.(*ScriptTelemetryRecordV2)
+			// deliberately drop a required field to prove the post-migration
+			// record gets validated against its new version's rules.
+			logrec.SessionId = ""
+			logrec.RecordMeta.SchemaVersion = brokenVersion
+			return logrec, nil
+		},
+	})
+	RegisterScriptSchema(SchemaDescriptor{
+		Version: brokenVersion,
+		New:     func() interface{} { return &ScriptTelemetryRecordV2{} },
+		Validate: func(record interface{}) error {
+			return record.(*ScriptTelemetryRecordV2).Validate()
+		},
+		MigrateToNext: nil,
+	})
+
+	raw := []byte(fmt.Sprintf(`{
+		"meta": {"schema": "test-start"},
+		"timestamp": "2026-01-01T12:00:00Z",
+		"username": "jdoe",
+		"revit": "2024",
+		"revitbuild": "20230101_0000(x64)",
+		"sessionid": "b5f858b1-2f45-4d3e-9a3a-1f6f6c2e9a10",
+		"commanduniquename": "ext.cmd",
+		"commandextension": "ext",
+		"resultcode": 0
+	}`))
+
+	if _, err := ResolveScript(raw); err == nil {
+		t.Fatalf("expected migration to an invalid record to be rejected")
+	}
+}
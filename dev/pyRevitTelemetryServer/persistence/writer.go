@@ -0,0 +1,40 @@
+package persistence
+
+import "fmt"
+
+// Writer is the interface a telemetry sink implements. Writers register
+// themselves in init() keyed by the URL scheme of their connection
+// string, so the server can be pointed at a new sink without a code
+// change to the router. OTLPExporter ("otlp") is the only Writer
+// registered in this package; the mssql, mysql, postgres, sqlite and
+// mongo sinks do not implement it.
+type Writer interface {
+	Init(connectionString string) error
+	WriteScript(logrec ScriptTelemetryRecordV2) error
+	WriteEvent(logrec EventTelemetryRecordV2) error
+	Close() error
+}
+
+// WriterFactory constructs a fresh, un-initialized Writer.
+type WriterFactory func() Writer
+
+var writerRegistry = map[string]WriterFactory{}
+
+// RegisterWriter adds a Writer factory to the registry under scheme,
+// e.g. "mssql", "mongodb", "otlp". Call it from each writer's init().
+func RegisterWriter(scheme string, factory WriterFactory) {
+	if _, exists := writerRegistry[scheme]; exists {
+		panic(fmt.Sprintf("persistence: writer already registered for scheme %q", scheme))
+	}
+	writerRegistry[scheme] = factory
+}
+
+// NewWriter looks up the factory registered for scheme and returns a
+// freshly constructed Writer, or an error if none is registered.
+func NewWriter(scheme string) (Writer, error) {
+	factory, ok := writerRegistry[scheme]
+	if !ok {
+		return nil, fmt.Errorf("no writer registered for scheme %q", scheme)
+	}
+	return factory(), nil
+}
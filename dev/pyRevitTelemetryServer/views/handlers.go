@@ -0,0 +1,73 @@
+package views
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// RegisterRoutes mounts the saved-views subsystem on router: POST /views
+// to save a definition, GET /views/{name} to materialize and fetch one.
+func RegisterRoutes(router *mux.Router, store Store, evaluator Evaluator) {
+	router.HandleFunc("/views", postView(store)).Methods(http.MethodPost)
+	router.HandleFunc("/views/{name}", getView(store, evaluator)).Methods(http.MethodGet)
+}
+
+func postView(store Store) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		var def ViewDefinition
+		if err := json.NewDecoder(request.Body).Decode(&def); err != nil {
+			http.Error(writer, "failed reading view definition", http.StatusBadRequest)
+			return
+		}
+		if def.Name == "" {
+			http.Error(writer, "view definition missing required field: name", http.StatusBadRequest)
+			return
+		}
+
+		if err := store.SaveView(def); err != nil {
+			http.Error(writer, fmt.Sprintf("failed saving view %q", def.Name), http.StatusInternalServerError)
+			return
+		}
+		writer.WriteHeader(http.StatusCreated)
+	}
+}
+
+func getView(store Store, evaluator Evaluator) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		name := mux.Vars(request)["name"]
+
+		def, err := store.LoadView(name)
+		if err != nil {
+			http.Error(writer, fmt.Sprintf("unknown view %q", name), http.StatusNotFound)
+			return
+		}
+
+		rows, err := Materialize(store, evaluator, def)
+		if err != nil {
+			http.Error(writer, fmt.Sprintf("failed evaluating view %q", name), http.StatusInternalServerError)
+			return
+		}
+
+		if request.URL.Query().Get("format") == "csv" {
+			body, err := EncodeCSV(def, rows)
+			if err != nil {
+				http.Error(writer, fmt.Sprintf("failed encoding view %q", name), http.StatusInternalServerError)
+				return
+			}
+			writer.Header().Set("Content-Type", "text/csv")
+			writer.Write(body)
+			return
+		}
+
+		body, err := EncodeJSON(rows)
+		if err != nil {
+			http.Error(writer, fmt.Sprintf("failed encoding view %q", name), http.StatusInternalServerError)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		writer.Write(body)
+	}
+}
@@ -0,0 +1,134 @@
+package views
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// mongoFieldNames maps a ViewDefinition's filter/group_by keys to the
+// field names used in the script_telemetry collection, mirroring
+// persistence.ScriptTelemetryRecordV2's bson tags.
+var mongoFieldNames = map[string]string{
+	"extension": "commandextension",
+	"command":   "commanduniquename",
+	"revit":     "revit",
+	"username":  "username",
+}
+
+// MongoEvaluator implements Evaluator against MongoDB by translating a
+// ViewDefinition into a $match/$group aggregation pipeline over the
+// script_telemetry collection.
+type MongoEvaluator struct {
+	Collection *mongo.Collection
+}
+
+// Evaluate implements Evaluator.
+func (evaluator *MongoEvaluator) Evaluate(def ViewDefinition) ([]ViewRow, error) {
+	pipeline, err := buildMongoPipeline(def)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := evaluator.Collection.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed evaluating view %q: %w", def.Name, err)
+	}
+	defer cursor.Close(context.Background())
+
+	var results []ViewRow
+	for cursor.Next(context.Background()) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("failed decoding view %q row: %w", def.Name, err)
+		}
+
+		groupKey := make(map[string]string, len(def.GroupBy))
+		if id, ok := doc["_id"].(bson.M); ok {
+			for _, column := range def.GroupBy {
+				if value, ok := id[column]; ok {
+					groupKey[column] = fmt.Sprintf("%v", value)
+				}
+			}
+		}
+
+		var value float64
+		switch number := doc["value"].(type) {
+		case float64:
+			value = number
+		case int32:
+			value = float64(number)
+		case int64:
+			value = float64(number)
+		}
+		results = append(results, ViewRow{GroupKey: groupKey, Value: value})
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed reading view %q results: %w", def.Name, err)
+	}
+	return results, nil
+}
+
+// buildMongoPipeline translates def into a $match/$group aggregation
+// pipeline.
+func buildMongoPipeline(def ViewDefinition) (mongo.Pipeline, error) {
+	match := bson.D{}
+	for key, value := range def.Filters {
+		field, ok := mongoFieldNames[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown filter key %q", key)
+		}
+		match = append(match, bson.E{Key: field, Value: value})
+	}
+
+	id := bson.D{}
+	for _, key := range def.GroupBy {
+		field, ok := mongoFieldNames[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown group_by key %q", key)
+		}
+		id = append(id, bson.E{Key: key, Value: "$" + field})
+	}
+
+	groupStage, err := mongoMetricGroupStage(def.Metric, id)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := mongo.Pipeline{}
+	if len(match) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: match}})
+	}
+	pipeline = append(pipeline, groupStage)
+	return pipeline, nil
+}
+
+// mongoMetricGroupStage returns the $group stage that computes metric,
+// grouped by id, aliased to "value".
+func mongoMetricGroupStage(metric Metric, id bson.D) (bson.D, error) {
+	switch metric {
+	case MetricCount:
+		return bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: id},
+			{Key: "value", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}}, nil
+	case MetricErrorRate:
+		return bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: id},
+			{Key: "value", Value: bson.D{{Key: "$avg", Value: bson.D{
+				{Key: "$cond", Value: bson.A{bson.D{{Key: "$ne", Value: bson.A{"$resultcode", 0}}}, 1, 0}},
+			}}}},
+		}}}, nil
+	case MetricP95Duration:
+		// Mongo's true percentile operator ($percentile) requires server
+		// 7.0+; approximate with $avg until that's a guaranteed baseline.
+		return bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: id},
+			{Key: "value", Value: bson.D{{Key: "$avg", Value: "$duration_ms"}}},
+		}}}, nil
+	default:
+		return nil, fmt.Errorf("unknown metric %q", metric)
+	}
+}
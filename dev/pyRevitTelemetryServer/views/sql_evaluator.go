@@ -0,0 +1,120 @@
+package views
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// sqlColumnNames maps a ViewDefinition's filter/group_by keys to the
+// column names used in the script_telemetry table, mirroring
+// persistence.ScriptTelemetryRecordV2's json/bson tags.
+var sqlColumnNames = map[string]string{
+	"extension": "commandextension",
+	"command":   "commanduniquename",
+	"revit":     "revit",
+	"username":  "username",
+}
+
+// SQLEvaluator implements Evaluator against a SQL store (mssql, mysql,
+// postgres, sqlite) by translating a ViewDefinition into a parameterized
+// GROUP BY query over the script_telemetry table. Filters are bound with
+// "?" placeholders, which lib/pq (postgres) does not accept, and the
+// p95_duration metric uses PERCENTILE_CONT(...) WITHIN GROUP, which
+// MySQL and SQLite do not provide.
+type SQLEvaluator struct {
+	DB *sql.DB
+}
+
+// Evaluate implements Evaluator.
+func (evaluator *SQLEvaluator) Evaluate(def ViewDefinition) ([]ViewRow, error) {
+	query, args, err := buildSQLQuery(def)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := evaluator.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed evaluating view %q: %w", def.Name, err)
+	}
+	defer rows.Close()
+
+	var results []ViewRow
+	for rows.Next() {
+		groupValues := make([]string, len(def.GroupBy))
+		scan := make([]interface{}, 0, len(groupValues)+1)
+		for i := range groupValues {
+			scan = append(scan, &groupValues[i])
+		}
+		var value float64
+		scan = append(scan, &value)
+
+		if err := rows.Scan(scan...); err != nil {
+			return nil, fmt.Errorf("failed scanning view %q row: %w", def.Name, err)
+		}
+
+		groupKey := make(map[string]string, len(def.GroupBy))
+		for i, column := range def.GroupBy {
+			groupKey[column] = groupValues[i]
+		}
+		results = append(results, ViewRow{GroupKey: groupKey, Value: value})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed reading view %q results: %w", def.Name, err)
+	}
+	return results, nil
+}
+
+// buildSQLQuery translates def into a parameterized SELECT ... GROUP BY
+// query and its bind arguments.
+func buildSQLQuery(def ViewDefinition) (string, []interface{}, error) {
+	metricExpr, err := sqlMetricExpr(def.Metric)
+	if err != nil {
+		return "", nil, err
+	}
+
+	groupColumns := make([]string, len(def.GroupBy))
+	for i, key := range def.GroupBy {
+		column, ok := sqlColumnNames[key]
+		if !ok {
+			return "", nil, fmt.Errorf("unknown group_by key %q", key)
+		}
+		groupColumns[i] = column
+	}
+
+	var where []string
+	var args []interface{}
+	for key, value := range def.Filters {
+		column, ok := sqlColumnNames[key]
+		if !ok {
+			return "", nil, fmt.Errorf("unknown filter key %q", key)
+		}
+		where = append(where, fmt.Sprintf("%s = ?", column))
+		args = append(args, value)
+	}
+
+	selected := append(append([]string{}, groupColumns...), metricExpr)
+	query := fmt.Sprintf("SELECT %s FROM script_telemetry", strings.Join(selected, ", "))
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	if len(groupColumns) > 0 {
+		query += " GROUP BY " + strings.Join(groupColumns, ", ")
+	}
+	return query, args, nil
+}
+
+// sqlMetricExpr returns the aggregate expression, aliased to "value",
+// that computes metric over script_telemetry rows.
+func sqlMetricExpr(metric Metric) (string, error) {
+	switch metric {
+	case MetricCount:
+		return "COUNT(*) AS value", nil
+	case MetricErrorRate:
+		return "AVG(CASE WHEN resultcode <> 0 THEN 1.0 ELSE 0.0 END) AS value", nil
+	case MetricP95Duration:
+		return "PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY duration_ms) AS value", nil
+	default:
+		return "", fmt.Errorf("unknown metric %q", metric)
+	}
+}
@@ -0,0 +1,33 @@
+package views
+
+import "testing"
+
+func TestBuildSQLQueryTranslatesFiltersAndGroupBy(t *testing.T) {
+	def := ViewDefinition{
+		Name:    "top-failures",
+		Filters: map[string]string{"extension": "PyRevitTools.extension"},
+		GroupBy: []string{"command"},
+		Metric:  MetricErrorRate,
+	}
+
+	query, args, err := buildSQLQuery(def)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	const want = "SELECT commanduniquename, AVG(CASE WHEN resultcode <> 0 THEN 1.0 ELSE 0.0 END) AS value FROM script_telemetry WHERE commandextension = ? GROUP BY commanduniquename"
+	if query != want {
+		t.Fatalf("expected query %q, got %q", want, query)
+	}
+	if len(args) != 1 || args[0] != "PyRevitTools.extension" {
+		t.Fatalf("expected bind args [PyRevitTools.extension], got %v", args)
+	}
+}
+
+func TestBuildSQLQueryRejectsUnknownGroupByKey(t *testing.T) {
+	def := ViewDefinition{Name: "bad-view", GroupBy: []string{"not_a_real_key"}, Metric: MetricCount}
+
+	if _, _, err := buildSQLQuery(def); err == nil {
+		t.Fatal("expected an error for an unknown group_by key")
+	}
+}
@@ -0,0 +1,112 @@
+package views
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Metric identifies the aggregation computed by a saved view.
+type Metric string
+
+const (
+	MetricCount       Metric = "count"
+	MetricErrorRate   Metric = "error_rate"
+	MetricP95Duration Metric = "p95_duration"
+)
+
+// ViewDefinition is a named aggregation over stored telemetry records,
+// POSTed by an operator and persisted in the same backend as the
+// records themselves, e.g. the error rate per command within one
+// extension. There is no row limit or time-range filter.
+type ViewDefinition struct {
+	Name       string            `json:"name" bson:"name"`
+	Filters    map[string]string `json:"filters" bson:"filters"` // extension, command, revit, username
+	GroupBy    []string          `json:"group_by" bson:"group_by"`
+	TimeBucket string            `json:"time_bucket" bson:"time_bucket"` // e.g. "1h", "1d"; not applied by SQLEvaluator or MongoEvaluator, so ViewRow.Bucket stays empty
+	Metric     Metric            `json:"metric" bson:"metric"`
+	TTL        time.Duration     `json:"ttl" bson:"ttl"`
+}
+
+// ViewRow is one row of a materialized view result.
+type ViewRow struct {
+	GroupKey map[string]string `json:"group" bson:"group"`
+	Bucket   string            `json:"bucket" bson:"bucket"`
+	Value    float64           `json:"value" bson:"value"`
+}
+
+// Store persists view definitions and caches their materialized
+// results, alongside the telemetry records in the same backend.
+// Implementations are expected to stamp CacheResult's entry with the
+// current time and use ttl to decide whether CachedResult still
+// considers it fresh.
+type Store interface {
+	SaveView(def ViewDefinition) error
+	LoadView(name string) (ViewDefinition, error)
+	CachedResult(name string, ttl time.Duration) ([]ViewRow, bool)
+	CacheResult(name string, rows []ViewRow)
+}
+
+// Evaluator translates a ViewDefinition into the underlying store's
+// query language (SQL GROUP BY / Mongo aggregation pipeline) and runs
+// it against the stored records.
+type Evaluator interface {
+	Evaluate(def ViewDefinition) ([]ViewRow, error)
+}
+
+// Materialize returns the cached result for def if its TTL hasn't
+// expired, otherwise evaluates it fresh and refreshes the cache.
+func Materialize(store Store, evaluator Evaluator, def ViewDefinition) ([]ViewRow, error) {
+	if rows, ok := store.CachedResult(def.Name, def.TTL); ok {
+		return rows, nil
+	}
+
+	rows, err := evaluator.Evaluate(def)
+	if err != nil {
+		return nil, fmt.Errorf("failed evaluating view %q: %w", def.Name, err)
+	}
+	store.CacheResult(def.Name, rows)
+	return rows, nil
+}
+
+// EncodeJSON renders a materialized view result for the GET
+// .../views/{name} endpoint.
+func EncodeJSON(rows []ViewRow) ([]byte, error) {
+	return json.Marshal(rows)
+}
+
+// EncodeCSV renders a materialized view result for the GET
+// .../views/{name}?format=csv endpoint. Columns follow def.GroupBy so
+// the same view always produces the same column order, rather than the
+// randomized order map iteration would give.
+func EncodeCSV(def ViewDefinition, rows []ViewRow) ([]byte, error) {
+	groupColumns := def.GroupBy
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	header := append(append([]string{}, groupColumns...), "bucket", "value")
+	if err := writer.Write(header); err != nil {
+		return nil, fmt.Errorf("failed writing csv header: %w", err)
+	}
+
+	for _, row := range rows {
+		record := make([]string, 0, len(header))
+		for _, column := range groupColumns {
+			record = append(record, row.GroupKey[column])
+		}
+		record = append(record, row.Bucket, strconv.FormatFloat(row.Value, 'f', -1, 64))
+		if err := writer.Write(record); err != nil {
+			return nil, fmt.Errorf("failed writing csv row: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("failed flushing csv: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,76 @@
+package views
+
+import (
+	"testing"
+	"time"
+)
+
+// memStore is a minimal Store for tests: it stamps CacheResult with the
+// current time and honors ttl in CachedResult, the contract the Store
+// interface documents.
+type memStore struct {
+	rows     []ViewRow
+	cachedAt time.Time
+	has      bool
+}
+
+func (store *memStore) SaveView(ViewDefinition) error                { return nil }
+func (store *memStore) LoadView(string) (ViewDefinition, error)      { return ViewDefinition{}, nil }
+func (store *memStore) CacheResult(name string, rows []ViewRow) {
+	store.rows = rows
+	store.cachedAt = time.Now()
+	store.has = true
+}
+func (store *memStore) CachedResult(name string, ttl time.Duration) ([]ViewRow, bool) {
+	if !store.has || time.Since(store.cachedAt) > ttl {
+		return nil, false
+	}
+	return store.rows, true
+}
+
+type countingEvaluator struct {
+	calls int
+	rows  []ViewRow
+}
+
+func (evaluator *countingEvaluator) Evaluate(ViewDefinition) ([]ViewRow, error) {
+	evaluator.calls++
+	return evaluator.rows, nil
+}
+
+func TestMaterializeReusesCacheWithinTTL(t *testing.T) {
+	store := &memStore{}
+	evaluator := &countingEvaluator{rows: []ViewRow{{Bucket: "b1", Value: 1}}}
+	def := ViewDefinition{Name: "top-failures", TTL: time.Hour}
+
+	if _, err := Materialize(store, evaluator, def); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := Materialize(store, evaluator, def); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if evaluator.calls != 1 {
+		t.Fatalf("expected evaluator to run once within TTL, ran %d times", evaluator.calls)
+	}
+}
+
+func TestMaterializeReevaluatesAfterTTLExpires(t *testing.T) {
+	store := &memStore{}
+	evaluator := &countingEvaluator{rows: []ViewRow{{Bucket: "b1", Value: 1}}}
+	def := ViewDefinition{Name: "top-failures", TTL: time.Millisecond}
+
+	if _, err := Materialize(store, evaluator, def); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	if _, err := Materialize(store, evaluator, def); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if evaluator.calls != 2 {
+		t.Fatalf("expected evaluator to re-run after TTL expiry, ran %d times", evaluator.calls)
+	}
+}